@@ -1,80 +1,45 @@
 package arguments
 
 import (
-	"github.com/hashicorp/terraform/tfdiags"
+	"fmt"
+	"time"
 )
 
-type Output struct {
-	Color     bool
-	Name      string
-	ViewType  ViewType
-	StatePath string
+// DefaultWatchInterval is the polling interval used by -watch when no
+// explicit duration is given.
+const DefaultWatchInterval = 5 * time.Second
+
+// WatchDuration is a flag.Value that backs the -watch[=duration] flag: it
+// behaves like a bool flag when given alone (enabling watch mode with
+// DefaultWatchInterval), but also accepts an explicit duration via
+// -watch=10s.
+type WatchDuration struct {
+	Enabled  bool
+	Duration time.Duration
 }
 
-func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
-	var diags tfdiags.Diagnostics
-	output := &Output{}
-
-	var noColor, jsonOutput, rawOutput bool
-	var statePath string
-	cmdFlags := defaultFlagSet("output")
-	cmdFlags.BoolVar(&noColor, "no-color", false, "no-color")
-	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
-	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
-	cmdFlags.StringVar(&statePath, "state", "", "path")
-
-	if err := cmdFlags.Parse(args); err != nil {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Failed to parse command-line flags",
-			err.Error(),
-		))
-	}
-
-	args = cmdFlags.Args()
-	if len(args) > 1 {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Unexpected argument",
-			"The output command expects exactly one argument with the name of an output variable or no arguments to show all outputs.",
-		))
-	}
-
-	if jsonOutput && rawOutput {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Invalid output format",
-			"The -raw and -json options are mutually-exclusive.",
-		))
-
-		// Since the desired output format is unknowable, fall back to default
-		jsonOutput = false
-		rawOutput = false
+func (w *WatchDuration) String() string {
+	if !w.Enabled {
+		return ""
 	}
+	return w.Duration.String()
+}
 
-	output.Color = !noColor
-	output.StatePath = statePath
-
-	if len(args) > 0 {
-		output.Name = args[0]
+func (w *WatchDuration) Set(s string) error {
+	if s == "" || s == "true" {
+		w.Enabled = true
+		w.Duration = DefaultWatchInterval
+		return nil
 	}
-
-	if rawOutput && output.Name == "" {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Output name required",
-			"You must give the name of a single output value when using the -raw option.",
-		))
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid -watch duration: %s", err)
 	}
-
-	switch {
-	case jsonOutput:
-		output.ViewType = ViewJSON
-	case rawOutput:
-		output.ViewType = ViewRaw
-	default:
-		output.ViewType = ViewHuman
-	}
-
-	return output, diags
+	w.Enabled = true
+	w.Duration = d
+	return nil
 }
+
+// IsBoolFlag lets the flag package accept a bare -watch with no "=value",
+// the same way it does for built-in bool flags.
+func (w *WatchDuration) IsBoolFlag() bool { return true }
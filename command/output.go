@@ -2,8 +2,14 @@ package command
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform/command/arguments"
 	"github.com/hashicorp/terraform/command/views"
 	"github.com/hashicorp/terraform/states"
 	"github.com/hashicorp/terraform/tfdiags"
@@ -16,9 +22,12 @@ type OutputCommand struct {
 }
 
 type outputArguments struct {
-	name      string
-	viewType  views.ViewType
-	statePath string
+	name          string
+	viewType      views.ViewType
+	format        views.OutputFormat
+	statePath     string
+	watch         bool
+	watchInterval time.Duration
 }
 
 func (c *OutputCommand) Run(cliArgs []string) int {
@@ -30,10 +39,41 @@ func (c *OutputCommand) Run(cliArgs []string) int {
 		return 1
 	}
 
-	view := views.NewOutput(args.viewType, c.View())
+	view := views.NewOutput(args.viewType, args.format, c.View())
 
-	// Fetch data from state
+	if args.watch {
+		return c.runWatch(args, view)
+	}
+
+	return c.runOnce(args, view)
+}
+
+// outputsForArgs fetches the current outputs for the given arguments and,
+// if a NAME was given, narrows the result down to the single resolved
+// output path.
+func (c *OutputCommand) outputsForArgs(args *outputArguments) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
 	outputs, diags := c.Outputs(args.statePath)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// A NAME argument may be a dotted/indexed path into a nested output
+	// value (e.g. "vpc.subnets[0].cidr") rather than a plain top-level
+	// output name, so resolve it before handing off to the view.
+	if args.name != "" {
+		resolvedName, ov, pathDiags := resolveOutputPath(args.name, outputs)
+		diags = diags.Append(pathDiags)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		outputs = map[string]*states.OutputValue{resolvedName: ov}
+	}
+
+	return outputs, diags
+}
+
+func (c *OutputCommand) runOnce(args *outputArguments, view views.Output) int {
+	outputs, diags := c.outputsForArgs(args)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
 		return 1
@@ -52,6 +92,68 @@ func (c *OutputCommand) Run(cliArgs []string) int {
 	return 0
 }
 
+// runWatch re-reads the outputs every args.watchInterval and renders each
+// change as it's observed, until interrupted. The first read is diffed
+// against an empty set of previous outputs, so every output is reported
+// as added; this renders the current values in full, through the same
+// OutputChange path used for every later tick, so that a viewer attaching
+// to the stream isn't left waiting for the next change and every line of
+// output (including under -raw) is delimited the same way from the start.
+func (c *OutputCommand) runWatch(args *outputArguments, view views.Output) int {
+	previous := map[string]*states.OutputValue{}
+
+	for {
+		outputs, diags := c.outputsForArgs(args)
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+
+		now := time.Now()
+		for _, change := range diffOutputChanges(previous, outputs) {
+			changeDiags := view.OutputChange(now, change)
+			view.Diagnostics(changeDiags)
+			if changeDiags.HasErrors() {
+				return 1
+			}
+		}
+
+		previous = outputs
+
+		select {
+		case <-time.After(args.watchInterval):
+		case <-c.ShutdownCh:
+			return 0
+		}
+	}
+}
+
+// diffOutputChanges compares two sets of outputs and returns, in a stable
+// name order, the changes needed to get from previous to current: added,
+// removed, or value/sensitivity changes. Unchanged outputs are omitted.
+func diffOutputChanges(previous, current map[string]*states.OutputValue) []views.OutputChange {
+	var changes []views.OutputChange
+
+	for name, cur := range current {
+		prev, existed := previous[name]
+		if !existed || prev.Sensitive != cur.Sensitive || !prev.Value.RawEquals(cur.Value) {
+			change := views.OutputChange{Name: name, New: cur}
+			if existed {
+				change.Old = prev
+			}
+			changes = append(changes, change)
+		}
+	}
+	for name, prev := range previous {
+		if _, stillExists := current[name]; !stillExists {
+			changes = append(changes, views.OutputChange{Name: name, Old: prev})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
 func (c *OutputCommand) ParseArguments(cliArgs []string) (*outputArguments, error) {
 	// Extract -no-color
 	cliArgs = c.Meta.process(cliArgs)
@@ -59,10 +161,14 @@ func (c *OutputCommand) ParseArguments(cliArgs []string) (*outputArguments, erro
 	args := &outputArguments{}
 
 	var jsonOutput, rawOutput bool
+	var format string
+	var watch arguments.WatchDuration
 	cmdFlags := c.Meta.defaultFlagSet("output")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
+	cmdFlags.StringVar(&format, "format", "", "format")
 	cmdFlags.StringVar(&args.statePath, "state", "", "path")
+	cmdFlags.Var(&watch, "watch", "watch")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(cliArgs); err != nil {
 		return nil, fmt.Errorf("Error parsing command-line flags: %s\n", err.Error())
@@ -74,14 +180,41 @@ func (c *OutputCommand) ParseArguments(cliArgs []string) (*outputArguments, erro
 			"of an output variable or no arguments to show all outputs.\n")
 	}
 
-	if jsonOutput && rawOutput {
-		return nil, fmt.Errorf("The -raw and -json options are mutually-exclusive.\n")
+	var outputFormat views.OutputFormat
+	switch format {
+	case "":
+		// no -format given
+	case "yaml":
+		outputFormat = views.OutputFormatYAML
+	case "hcl":
+		outputFormat = views.OutputFormatHCL
+	case "env":
+		outputFormat = views.OutputFormatEnv
+	case "dotenv":
+		outputFormat = views.OutputFormatDotenv
+	default:
+		return nil, fmt.Errorf("The -format option only supports \"yaml\", \"hcl\", \"env\", and\n" +
+			"\"dotenv\", but got " + strconv.Quote(format) + ".\n")
+	}
+
+	formatCount := 0
+	for _, set := range []bool{jsonOutput, rawOutput, outputFormat != ""} {
+		if set {
+			formatCount++
+		}
+	}
+	if formatCount > 1 {
+		return nil, fmt.Errorf("The -json, -raw, and -format options are mutually-exclusive.\n")
 	}
 
 	if rawOutput && len(cliArgs) == 0 {
 		return nil, fmt.Errorf("You must give the name of a single output value when using the -raw option.\n")
 	}
 
+	args.format = outputFormat
+	args.watch = watch.Enabled
+	args.watchInterval = watch.Duration
+
 	switch {
 	case jsonOutput:
 		args.viewType = views.ViewJSON
@@ -98,9 +231,74 @@ func (c *OutputCommand) ParseArguments(cliArgs []string) (*outputArguments, erro
 	return args, nil
 }
 
+// resolveOutputPath interprets name as an HCL traversal rooted at a
+// top-level output name, so that callers can ask for a nested value such
+// as "vpc.subnets[0].cidr" or `cluster["primary"].endpoint` instead of
+// being limited to a plain output name. It returns the output value that
+// the full path resolves to, preserving the sensitivity of the root
+// output it was drawn from.
+func resolveOutputPath(name string, outputs map[string]*states.OutputValue) (string, *states.OutputValue, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// Fast path: an exact top-level output name. This also covers output
+	// names that aren't valid HCL traversals, so existing exact-match
+	// behavior is unaffected.
+	if ov, ok := outputs[name]; ok {
+		return name, ov, diags
+	}
+
+	traversal, travDiags := hclsyntax.ParseTraversalAbs([]byte(name), "<output-name>", hcl.InitialPos)
+	if travDiags.HasErrors() || len(traversal) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Output not found",
+			fmt.Sprintf("The output variable %q is not defined in the root module.", name),
+		))
+		return name, nil, diags
+	}
+
+	root, ok := traversal[0].(hcl.TraverseRoot)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output path",
+			fmt.Sprintf("The output path %q must begin with the name of a root module output value.", name),
+		))
+		return name, nil, diags
+	}
+
+	rootOV, ok := outputs[root.Name]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Output not found",
+			fmt.Sprintf("The output variable %q is not defined in the root module.", root.Name),
+		))
+		return name, nil, diags
+	}
+
+	value, travDiags := traversal[1:].TraverseRel(rootOV.Value)
+	if travDiags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output path",
+			fmt.Sprintf("Could not evaluate %q: %s", name, travDiags.Error()),
+		))
+		return name, nil, diags
+	}
+
+	return name, &states.OutputValue{Value: value, Sensitive: rootOV.Sensitive}, diags
+}
+
 func (c *OutputCommand) Outputs(statePath string) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
+	// A -state URL pointing at a foreign state file is read directly and
+	// read-only, without going through backend initialization at all.
+	if isRemoteStateSource(statePath) {
+		return c.remoteOutputs(statePath)
+	}
+
 	// Allow state path override
 	if statePath != "" {
 		c.Meta.statePath = statePath
@@ -151,10 +349,20 @@ Usage: terraform output [options] [NAME]
   the outputs for the root module.  If NAME is not specified, all
   outputs are printed.
 
+  NAME may also be a path into a nested output value, such as
+  "vpc.subnets[0].cidr" or "cluster[\"primary\"].endpoint", to select a
+  value nested inside a list, map, or object without piping through
+  another tool.
+
 Options:
 
   -state=path      Path to the state file to read. Defaults to
-                   "terraform.tfstate".
+                   "terraform.tfstate". May also be a URL
+                   (http://, https://, s3://, gs://, or
+                   tfe://<org>/<workspace>) to read a state file
+                   directly without a matching backend configuration.
+                   This is strictly read-only and never touches the
+                   current working directory's backend.
 
   -no-color        If specified, output won't contain any color.
 
@@ -165,6 +373,22 @@ Options:
                    converted to a string, will print the raw
                    string directly, rather than a human-oriented
                    representation of the value.
+
+  -format=format   Print output in an alternative, machine-readable
+                   format. Accepts "yaml", "hcl", "env", or "dotenv".
+                   Mutually exclusive with -json and -raw. The "env"
+                   and "dotenv" formats only support outputs whose
+                   values are strings, numbers, or booleans, and refuse
+                   to print any output marked sensitive; use -json or
+                   -raw if you need to access a sensitive output's
+                   value in an automated way.
+
+  -watch[=5s]      Watch the state for changes and print each output
+                   update as it's observed, instead of exiting after
+                   printing the current values. Defaults to polling
+                   every 5 seconds; an explicit interval may be given,
+                   e.g. -watch=10s. With -json, changes are printed as
+                   newline-delimited JSON events.
 `
 	return strings.TrimSpace(helpText)
 }
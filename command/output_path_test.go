@@ -0,0 +1,85 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/states"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestResolveOutputPath(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"plain": {Value: cty.StringVal("hello")},
+		"vpc": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"subnets": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{"cidr": cty.StringVal("10.0.0.0/24")}),
+				}),
+			}),
+		},
+	}
+
+	t.Run("exact top-level name", func(t *testing.T) {
+		name, ov, diags := resolveOutputPath("plain", outputs)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		if name != "plain" {
+			t.Errorf("wrong name: got %q", name)
+		}
+		if ov.Value.AsString() != "hello" {
+			t.Errorf("wrong value: got %#v", ov.Value)
+		}
+	})
+
+	t.Run("nested path", func(t *testing.T) {
+		_, ov, diags := resolveOutputPath("vpc.subnets[0].cidr", outputs)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		if ov.Value.AsString() != "10.0.0.0/24" {
+			t.Errorf("wrong value: got %#v", ov.Value)
+		}
+	})
+
+	t.Run("nested path preserves sensitivity of the root output", func(t *testing.T) {
+		sensitiveOutputs := map[string]*states.OutputValue{
+			"vpc": {
+				Value: cty.ObjectVal(map[string]cty.Value{
+					"subnets": cty.ListVal([]cty.Value{
+						cty.ObjectVal(map[string]cty.Value{"cidr": cty.StringVal("10.0.0.0/24")}),
+					}),
+				}),
+				Sensitive: true,
+			},
+		}
+		_, ov, diags := resolveOutputPath("vpc.subnets[0].cidr", sensitiveOutputs)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		if !ov.Sensitive {
+			t.Errorf("expected the resolved value to inherit the root output's sensitivity")
+		}
+	})
+
+	t.Run("out of range index", func(t *testing.T) {
+		_, _, diags := resolveOutputPath("vpc.subnets[5].cidr", outputs)
+		if !diags.HasErrors() {
+			t.Fatalf("expected an error for an out-of-range index")
+		}
+	})
+
+	t.Run("missing root output", func(t *testing.T) {
+		_, _, diags := resolveOutputPath("nonexistent", outputs)
+		if !diags.HasErrors() {
+			t.Fatalf("expected an error for a missing output")
+		}
+	})
+
+	t.Run("missing nested attribute", func(t *testing.T) {
+		_, _, diags := resolveOutputPath("vpc.nonexistent", outputs)
+		if !diags.HasErrors() {
+			t.Fatalf("expected an error for a missing nested attribute")
+		}
+	})
+}
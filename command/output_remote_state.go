@@ -0,0 +1,368 @@
+package command
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/states"
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+// remoteStateSchemes are the -state URL schemes that are read directly,
+// bypassing backend initialization entirely. Anything else is treated as
+// a local file path, same as before.
+var remoteStateSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"s3":    true,
+	"gs":    true,
+	"tfe":   true,
+}
+
+// isRemoteStateSource reports whether statePath names a foreign state
+// file to read directly, rather than a local path to resolve through the
+// configured backend.
+func isRemoteStateSource(statePath string) bool {
+	u, err := url.Parse(statePath)
+	if err != nil {
+		return false
+	}
+	return remoteStateSchemes[u.Scheme]
+}
+
+// remoteOutputs fetches a state file straight from the URL named by
+// rawURL and returns its root module outputs. This never touches the
+// working directory's backend configuration or writes anything back, so
+// it's safe to point at state that belongs to an entirely different
+// configuration.
+//
+// Every scheme below is implemented over plain net/http rather than a
+// cloud provider SDK, so that this read-only convenience command doesn't
+// pull in a heavyweight, provider-specific client just to fetch one file.
+func (c *OutputCommand) remoteOutputs(rawURL string) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Invalid -state URL %q: %s", rawURL, err))
+		return nil, diags
+	}
+
+	var body io.ReadCloser
+	switch u.Scheme {
+	case "http", "https":
+		body, err = fetchHTTPState(u)
+	case "s3":
+		body, err = fetchS3State(u)
+	case "gs":
+		body, err = fetchGCSState(u)
+	case "tfe":
+		body, err = fetchTFEState(u)
+	default:
+		err = fmt.Errorf("unsupported -state URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to read state from %s: %s", rawURL, err))
+		return nil, diags
+	}
+	defer body.Close()
+
+	sf, err := statefile.Read(body)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to parse state read from %s: %s", rawURL, err))
+		return nil, diags
+	}
+
+	state := sf.State
+	if state == nil {
+		state = states.NewState()
+	}
+
+	return state.RootModule().OutputValues, diags
+}
+
+// fetchHTTPState reads a state file served directly over http(s). A
+// bearer token in TF_HTTP_TOKEN takes priority over basic auth via
+// TF_HTTP_USERNAME/TF_HTTP_PASSWORD, mirroring the http backend's own
+// auth options.
+func fetchHTTPState(u *url.URL) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case os.Getenv("TF_HTTP_TOKEN") != "":
+		req.Header.Set("Authorization", "Bearer "+os.Getenv("TF_HTTP_TOKEN"))
+	case os.Getenv("TF_HTTP_USERNAME") != "":
+		req.SetBasicAuth(os.Getenv("TF_HTTP_USERNAME"), os.Getenv("TF_HTTP_PASSWORD"))
+	}
+
+	return doRemoteStateRequest(req)
+}
+
+// fetchS3State reads a state file from s3://<bucket>/<key>, signing a
+// plain HTTPS GET with AWS Signature Version 4 using the same
+// environment variables the s3 backend itself honors
+// (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN,
+// AWS_REGION/AWS_DEFAULT_REGION).
+func fetchS3State(u *url.URL) (io.ReadCloser, error) {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to read s3:// state")
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s3Endpoint(u, region), nil)
+	if err != nil {
+		return nil, err
+	}
+	signAWSV4Request(req, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), region, "s3", time.Now().UTC())
+
+	return doRemoteStateRequest(req)
+}
+
+// s3Endpoint builds the virtual-hosted-style URL for u's bucket (u.Host)
+// and key (u.Path) in region, going through url.URL so that the key is
+// correctly percent-encoded rather than copied into the URL as-is.
+func s3Endpoint(u *url.URL, region string) string {
+	return (&url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", u.Host, region),
+		Path:   u.Path,
+	}).String()
+}
+
+// fetchGCSState reads a state file from gs://<bucket>/<object> via the GCS
+// XML API's S3-interoperability mode, which accepts the same AWS
+// Signature Version 4 scheme as fetchS3State. HMAC keys for this mode are
+// created in the GCS console and supplied via GOOGLE_HMAC_ACCESS_KEY_ID
+// and GOOGLE_HMAC_SECRET.
+func fetchGCSState(u *url.URL) (io.ReadCloser, error) {
+	accessKey := os.Getenv("GOOGLE_HMAC_ACCESS_KEY_ID")
+	secretKey := os.Getenv("GOOGLE_HMAC_SECRET")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("GOOGLE_HMAC_ACCESS_KEY_ID and GOOGLE_HMAC_SECRET must be set to read gs:// state")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, gcsEndpoint(u), nil)
+	if err != nil {
+		return nil, err
+	}
+	signAWSV4Request(req, accessKey, secretKey, "", "auto", "s3", time.Now().UTC())
+
+	return doRemoteStateRequest(req)
+}
+
+// gcsEndpoint builds the GCS XML API URL for u's bucket (u.Host) and
+// object (u.Path), going through url.URL so that the object name is
+// correctly percent-encoded rather than copied into the URL as-is.
+func gcsEndpoint(u *url.URL) string {
+	return (&url.URL{
+		Scheme: "https",
+		Host:   "storage.googleapis.com",
+		Path:   "/" + u.Host + u.Path,
+	}).String()
+}
+
+// tfeWorkspace and tfeStateVersion are the small slices of the TFE API's
+// JSON:API responses that fetchTFEState needs.
+type tfeWorkspace struct {
+	Data struct {
+		Relationships struct {
+			CurrentStateVersion struct {
+				Links struct {
+					Related string `json:"related"`
+				} `json:"links"`
+			} `json:"current-state-version"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+type tfeStateVersion struct {
+	Data struct {
+		Attributes struct {
+			DownloadURL string `json:"hosted-state-download-url"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// fetchTFEState reads the current state version for tfe://<org>/<workspace>
+// from Terraform Cloud/Enterprise over its plain REST API. The API address
+// and token come from the standard TFE_ADDRESS and TFE_TOKEN environment
+// variables.
+func fetchTFEState(u *url.URL) (io.ReadCloser, error) {
+	org := u.Host
+	workspace := strings.Trim(u.Path, "/")
+	if org == "" || workspace == "" {
+		return nil, fmt.Errorf(`tfe:// URL must be of the form "tfe://<org>/<workspace>"`)
+	}
+
+	token := os.Getenv("TFE_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("TFE_TOKEN must be set to read state from Terraform Cloud/Enterprise")
+	}
+
+	address := os.Getenv("TFE_ADDRESS")
+	if address == "" {
+		address = "https://app.terraform.io"
+	}
+
+	var ws tfeWorkspace
+	wsURL := fmt.Sprintf("%s/api/v2/organizations/%s/workspaces/%s", address, org, workspace)
+	if err := fetchTFEJSON(wsURL, token, &ws); err != nil {
+		return nil, fmt.Errorf("reading workspace %s/%s: %s", org, workspace, err)
+	}
+
+	svURL := ws.Data.Relationships.CurrentStateVersion.Links.Related
+	if svURL == "" {
+		return nil, fmt.Errorf("workspace %s/%s has no current state version", org, workspace)
+	}
+	if strings.HasPrefix(svURL, "/") {
+		svURL = address + svURL
+	}
+
+	var sv tfeStateVersion
+	if err := fetchTFEJSON(svURL, token, &sv); err != nil {
+		return nil, fmt.Errorf("reading current state version: %s", err)
+	}
+	if sv.Data.Attributes.DownloadURL == "" {
+		return nil, fmt.Errorf("current state version has no download URL")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, sv.Data.Attributes.DownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRemoteStateRequest(req)
+}
+
+// fetchTFEJSON issues an authenticated GET against the TFE API and decodes
+// the JSON:API response body into out.
+func fetchTFEJSON(rawURL, token string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+
+	resp, err := remoteStateHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// doRemoteStateRequest executes req and returns its body if the request
+// succeeded, closing the response and returning an error otherwise.
+func doRemoteStateRequest(req *http.Request) (io.ReadCloser, error) {
+	resp, err := remoteStateHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// remoteStateHTTPClient is shared by every -state URL fetcher above. A
+// bounded timeout keeps an unresponsive remote state source from hanging
+// the command indefinitely, which matters most under -watch's polling
+// loop.
+var remoteStateHTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// signAWSV4Request signs req in place using AWS Signature Version 4,
+// implemented directly against the standard library so that these
+// read-only fetchers don't need a cloud provider SDK dependency. It's
+// also used for GCS's XML API, which accepts the same scheme for
+// HMAC-keyed requests. now is taken as a parameter, rather than read
+// internally, so the signing logic can be exercised by tests with a
+// fixed timestamp.
+func signAWSV4Request(req *http.Request, accessKey, secretKey, sessionToken, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	payloadHash := sha256Hex(nil)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if sessionToken != "" {
+		req.Header.Set("x-amz-security-token", sessionToken)
+	}
+
+	headerNames := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headerNames {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = req.URL.Host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", h, value)
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/%s/aws4_request", dateStamp, region, service)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256(hmacSHA256(hmacSHA256(hmacSHA256([]byte("AWS4"+secretKey), dateStamp), region), service), "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, credentialScope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/command/views"
+	"github.com/hashicorp/terraform/states"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDiffOutputChanges(t *testing.T) {
+	unchanged := &states.OutputValue{Value: cty.StringVal("same")}
+	oldValue := &states.OutputValue{Value: cty.StringVal("old")}
+	newValue := &states.OutputValue{Value: cty.StringVal("new")}
+	removedValue := &states.OutputValue{Value: cty.StringVal("gone")}
+	addedValue := &states.OutputValue{Value: cty.StringVal("fresh")}
+	nowSensitive := &states.OutputValue{Value: cty.StringVal("same"), Sensitive: true}
+
+	previous := map[string]*states.OutputValue{
+		"unchanged":           unchanged,
+		"changed":             oldValue,
+		"removed":             removedValue,
+		"sensitivity_changed": unchanged,
+	}
+	current := map[string]*states.OutputValue{
+		"unchanged":           unchanged,
+		"changed":             newValue,
+		"added":               addedValue,
+		"sensitivity_changed": nowSensitive,
+	}
+
+	changes := diffOutputChanges(previous, current)
+
+	byName := map[string]views.OutputChange{}
+	for _, c := range changes {
+		byName[c.Name] = c
+	}
+
+	if _, ok := byName["unchanged"]; ok {
+		t.Errorf("unchanged output should not appear in the diff")
+	}
+
+	if c, ok := byName["changed"]; !ok {
+		t.Errorf("expected a change for %q", "changed")
+	} else if c.Old != oldValue || c.New != newValue {
+		t.Errorf("wrong old/new for %q: %#v", "changed", c)
+	}
+
+	if c, ok := byName["added"]; !ok {
+		t.Errorf("expected a change for %q", "added")
+	} else if c.Old != nil || c.New != addedValue {
+		t.Errorf("added output should have a nil Old: %#v", c)
+	}
+
+	if c, ok := byName["removed"]; !ok {
+		t.Errorf("expected a change for %q", "removed")
+	} else if c.New != nil || c.Old != removedValue {
+		t.Errorf("removed output should have a nil New: %#v", c)
+	}
+
+	if c, ok := byName["sensitivity_changed"]; !ok {
+		t.Errorf("a sensitivity-only change should still be reported")
+	} else if !c.New.Sensitive {
+		t.Errorf("expected the reported change to reflect the new sensitivity")
+	}
+}
+
+func TestDiffOutputChanges_firstTickIsAllAdded(t *testing.T) {
+	// runWatch diffs its first read against an empty previous set so that
+	// it renders through the same OutputChange path as every later tick;
+	// this is the behavior that relies on.
+	current := map[string]*states.OutputValue{
+		"a": {Value: cty.StringVal("1")},
+		"b": {Value: cty.StringVal("2")},
+	}
+
+	changes := diffOutputChanges(map[string]*states.OutputValue{}, current)
+	if len(changes) != len(current) {
+		t.Fatalf("expected %d changes, got %d", len(current), len(changes))
+	}
+	for _, c := range changes {
+		if c.Old != nil {
+			t.Errorf("expected %q to have a nil Old on the first tick, got %#v", c.Name, c.Old)
+		}
+	}
+}
@@ -0,0 +1,666 @@
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/states"
+	"github.com/hashicorp/terraform/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OutputFormat selects one of the machine-readable rendering modes
+// available via the output command's -format flag. It is distinct from
+// ViewType because these formats only apply to the output command and
+// each requires its own serialization rules, rather than sharing the
+// generic human/JSON/raw split used elsewhere.
+type OutputFormat string
+
+const (
+	OutputFormatNone   OutputFormat = ""
+	OutputFormatYAML   OutputFormat = "yaml"
+	OutputFormatHCL    OutputFormat = "hcl"
+	OutputFormatEnv    OutputFormat = "env"
+	OutputFormatDotenv OutputFormat = "dotenv"
+)
+
+// OutputChange describes a single output's transition from Old to New, as
+// observed by the output command's -watch mode. New is nil when the
+// output was removed; Old is nil when the output is newly added.
+type OutputChange struct {
+	Name string
+	Old  *states.OutputValue
+	New  *states.OutputValue
+}
+
+// Output is the view used by the "terraform output" command.
+type Output interface {
+	Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics
+	OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics
+	Diagnostics(diags tfdiags.Diagnostics)
+}
+
+// NewOutput returns an Output view appropriate for the given arguments. A
+// non-empty format takes priority over vt, since -format is mutually
+// exclusive with -json/-raw at the argument-parsing layer.
+func NewOutput(vt ViewType, format OutputFormat, view *View) Output {
+	switch format {
+	case OutputFormatYAML:
+		return &OutputYAML{view: view}
+	case OutputFormatHCL:
+		return &OutputHCL{view: view}
+	case OutputFormatEnv:
+		return &OutputEnv{view: view, quoted: false}
+	case OutputFormatDotenv:
+		return &OutputEnv{view: view, quoted: true}
+	}
+
+	switch vt {
+	case ViewJSON:
+		return &OutputJSON{view: view}
+	case ViewRaw:
+		return &OutputRaw{view: view}
+	default:
+		return &OutputHuman{view: view}
+	}
+}
+
+// selectOutputs returns the outputs to render: either the single named
+// output, or the full sorted set when name is empty.
+func selectOutputs(name string, outputs map[string]*states.OutputValue) ([]string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if name != "" {
+		if _, ok := outputs[name]; !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Output not found",
+				fmt.Sprintf("The output variable %q is not defined in the root module.", name),
+			))
+			return nil, diags
+		}
+		return []string{name}, diags
+	}
+
+	names := make([]string, 0, len(outputs))
+	for n := range outputs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, diags
+}
+
+type OutputHuman struct {
+	view *View
+}
+
+func (v *OutputHuman) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectOutputs(name, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if len(outputs) == 0 {
+		v.view.output("No outputs found.\n")
+		return diags
+	}
+
+	var buf bytes.Buffer
+	for _, n := range names {
+		repr := outputValueRepr(outputs[n])
+		if name != "" {
+			fmt.Fprintf(&buf, "%s\n", repr)
+		} else {
+			fmt.Fprintf(&buf, "%s = %s\n", n, repr)
+		}
+	}
+	v.view.output(buf.String())
+	return diags
+}
+
+func (v *OutputHuman) OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics {
+	ts := at.Format("15:04:05")
+	if change.New == nil {
+		v.view.output(fmt.Sprintf("%s  %s was removed\n", ts, change.Name))
+		return nil
+	}
+	v.view.output(fmt.Sprintf("%s  %s = %s\n", ts, change.Name, outputValueRepr(change.New)))
+	return nil
+}
+
+func (v *OutputHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+type OutputJSON struct {
+	view *View
+}
+
+func (v *OutputJSON) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectOutputs(name, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if name != "" {
+		jsonOutput, err := ctyjson.Marshal(outputs[name].Value, outputs[name].Value.Type())
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error serializing JSON",
+				fmt.Sprintf("While serializing output %q, the following error occurred: %s", name, err),
+			))
+			return diags
+		}
+		v.view.output(string(jsonOutput) + "\n")
+		return diags
+	}
+
+	jsonOutputs := map[string]interface{}{}
+	for _, n := range names {
+		value, err := jsonOutputValue(outputs[n])
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error serializing JSON",
+				fmt.Sprintf("While serializing output %q, the following error occurred: %s", n, err),
+			))
+			return diags
+		}
+		jsonOutputs[n] = value
+	}
+
+	jsonOutputsBytes, err := json.MarshalIndent(jsonOutputs, "", "  ")
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error serializing JSON",
+			fmt.Sprintf("While serializing outputs, the following error occurred: %s", err),
+		))
+		return diags
+	}
+	v.view.output(string(jsonOutputsBytes) + "\n")
+	return diags
+}
+
+// OutputChange emits a single newline-delimited JSON event describing the
+// change, of the form {"timestamp", "name", "old", "new"}, so that -watch
+// output can be consumed as a stream by other tools.
+func (v *OutputJSON) OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	event := map[string]interface{}{
+		"timestamp": at.Format(time.RFC3339),
+		"name":      change.Name,
+	}
+
+	if change.Old != nil {
+		old, err := jsonOutputValue(change.Old)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error serializing JSON",
+				fmt.Sprintf("While serializing the previous value of output %q, the following error occurred: %s", change.Name, err),
+			))
+			return diags
+		}
+		event["old"] = old
+	} else {
+		event["old"] = nil
+	}
+
+	if change.New != nil {
+		newValue, err := jsonOutputValue(change.New)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error serializing JSON",
+				fmt.Sprintf("While serializing the new value of output %q, the following error occurred: %s", change.Name, err),
+			))
+			return diags
+		}
+		event["new"] = newValue
+	} else {
+		event["new"] = nil
+	}
+
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error serializing JSON",
+			fmt.Sprintf("While serializing the change to output %q, the following error occurred: %s", change.Name, err),
+		))
+		return diags
+	}
+	v.view.output(string(eventBytes) + "\n")
+	return diags
+}
+
+func (v *OutputJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// jsonOutputValue renders a single output value in the {"sensitive",
+// "type", "value"} shape used by both the bulk and -watch JSON output.
+func jsonOutputValue(ov *states.OutputValue) (map[string]interface{}, error) {
+	valueBytes, err := ctyjson.Marshal(ov.Value, ov.Value.Type())
+	if err != nil {
+		return nil, err
+	}
+	typeBytes, err := ctyjson.MarshalType(ov.Value.Type())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"sensitive": ov.Sensitive,
+		"type":      json.RawMessage(typeBytes),
+		"value":     json.RawMessage(valueBytes),
+	}, nil
+}
+
+type OutputRaw struct {
+	view *View
+}
+
+func (v *OutputRaw) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	ov, ok := outputs[name]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Output not found",
+			fmt.Sprintf("The output variable %q is not defined in the root module.", name),
+		))
+		return diags
+	}
+
+	rawValue, valueDiags := rawOutputValue(name, ov.Value)
+	diags = diags.Append(valueDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	v.view.output(rawValue)
+	return diags
+}
+
+// OutputChange prints the updated scalar followed by a newline, so that
+// successive -watch updates are separated on the stream; plain -raw output
+// has no such separator since it's expected to be a single value. The
+// value is validated the same way as Output, so an output that isn't a
+// plain string, number, or bool produces a diagnostic instead of silently
+// rendering as a blank line on every tick.
+func (v *OutputRaw) OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics {
+	if change.New == nil {
+		return nil
+	}
+
+	rawValue, diags := rawOutputValue(change.Name, change.New.Value)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	v.view.output(rawValue + "\n")
+	return diags
+}
+
+func (v *OutputRaw) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// rawOutputValue validates that value is a type -raw can print and, if so,
+// renders it. The validation is shared by Output and OutputChange so that
+// an unsupported value is always reported as a diagnostic rather than
+// rendered as an empty string.
+func rawOutputValue(name string, value cty.Value) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if !value.Type().Equals(cty.String) && !value.Type().Equals(cty.Number) && !value.Type().Equals(cty.Bool) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported value for -raw",
+			fmt.Sprintf("The -raw option only supports strings, numbers, and booleans, but output %q has a value of type %s.", name, value.Type().FriendlyName()),
+		))
+		return "", diags
+	}
+	if value.IsNull() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported value for -raw",
+			fmt.Sprintf("The value for output %q is null, so -raw cannot print it.", name),
+		))
+		return "", diags
+	}
+
+	return rawScalar(value), nil
+}
+
+// OutputEnv renders outputs that reduce to a single KEY=value pair per
+// output, used by both the env and dotenv formats.
+type OutputEnv struct {
+	view *View
+	// quoted renders values the way a .env file is typically written,
+	// with double-quoted values suitable for direct use with tools that
+	// read .env files. Unquoted (plain env) output is meant for eval'ing
+	// directly into a shell, e.g. `eval "$(terraform output -format=env)"`.
+	quoted bool
+}
+
+func (v *OutputEnv) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectOutputs(name, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	var buf bytes.Buffer
+	for _, n := range names {
+		ov := outputs[n]
+		if ov.Sensitive {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot format sensitive output",
+				fmt.Sprintf("Output %q is sensitive, so it cannot be written in env/dotenv format. Use -json or -raw if you need to access its value in an automated way.", n),
+			))
+			continue
+		}
+		rawValue, err := scalarRawValue(ov.Value)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot format output as an environment variable",
+				fmt.Sprintf("Output %q is %s. Only strings, numbers, and booleans can be converted to env/dotenv format.", n, err),
+			))
+			continue
+		}
+		if v.quoted {
+			fmt.Fprintf(&buf, "%s=%q\n", n, rawValue)
+		} else {
+			fmt.Fprintf(&buf, "%s=%s\n", n, rawValue)
+		}
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+	v.view.output(buf.String())
+	return diags
+}
+
+func (v *OutputEnv) OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if change.New == nil {
+		v.view.output(fmt.Sprintf("# %s removed\n", change.Name))
+		return diags
+	}
+	if change.New.Sensitive {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot format sensitive output",
+			fmt.Sprintf("Output %q is sensitive, so it cannot be written in env/dotenv format. Use -json or -raw if you need to access its value in an automated way.", change.Name),
+		))
+		return diags
+	}
+	rawValue, err := scalarRawValue(change.New.Value)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot format output as an environment variable",
+			fmt.Sprintf("Output %q is %s. Only strings, numbers, and booleans can be converted to env/dotenv format.", change.Name, err),
+		))
+		return diags
+	}
+	if v.quoted {
+		v.view.output(fmt.Sprintf("%s=%q\n", change.Name, rawValue))
+	} else {
+		v.view.output(fmt.Sprintf("%s=%s\n", change.Name, rawValue))
+	}
+	return diags
+}
+
+func (v *OutputEnv) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+type OutputYAML struct {
+	view *View
+}
+
+func (v *OutputYAML) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectOutputs(name, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	target, err := buildYAMLTarget(name, names, outputs)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error serializing YAML",
+			err.Error(),
+		))
+		return diags
+	}
+
+	yamlBytes, err := yaml.Marshal(target)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error serializing YAML",
+			fmt.Sprintf("While serializing outputs, the following error occurred: %s", err),
+		))
+		return diags
+	}
+	v.view.output(string(yamlBytes))
+	return diags
+}
+
+func (v *OutputYAML) OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if change.New == nil {
+		v.view.output(fmt.Sprintf("# %s removed\n", change.Name))
+		return diags
+	}
+
+	target, err := buildYAMLTarget("", []string{change.Name}, map[string]*states.OutputValue{change.Name: change.New})
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error serializing YAML",
+			err.Error(),
+		))
+		return diags
+	}
+	yamlBytes, err := yaml.Marshal(target)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error serializing YAML",
+			fmt.Sprintf("While serializing output %q, the following error occurred: %s", change.Name, err),
+		))
+		return diags
+	}
+	v.view.output(string(yamlBytes))
+	return diags
+}
+
+func (v *OutputYAML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func buildYAMLTarget(name string, names []string, outputs map[string]*states.OutputValue) (interface{}, error) {
+	if name != "" {
+		ov := outputs[name]
+		if ov.Sensitive {
+			return "(sensitive value)", nil
+		}
+		return ctyValueToInterface(ov.Value)
+	}
+
+	result := map[string]interface{}{}
+	for _, n := range names {
+		ov := outputs[n]
+		if ov.Sensitive {
+			result[n] = "(sensitive value)"
+			continue
+		}
+		v, err := ctyValueToInterface(ov.Value)
+		if err != nil {
+			return nil, fmt.Errorf("while converting output %q: %s", n, err)
+		}
+		result[n] = v
+	}
+	return result, nil
+}
+
+type OutputHCL struct {
+	view *View
+}
+
+func (v *OutputHCL) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	names, diags := selectOutputs(name, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	var buf bytes.Buffer
+	if name != "" {
+		fmt.Fprintf(&buf, "%s\n", hclValueRepr(outputs[name]))
+	} else {
+		for _, n := range names {
+			fmt.Fprintf(&buf, "%s = %s\n", n, hclValueRepr(outputs[n]))
+		}
+	}
+	v.view.output(buf.String())
+	return diags
+}
+
+func (v *OutputHCL) OutputChange(at time.Time, change OutputChange) tfdiags.Diagnostics {
+	if change.New == nil {
+		v.view.output(fmt.Sprintf("# %s removed\n", change.Name))
+		return nil
+	}
+	v.view.output(fmt.Sprintf("%s = %s\n", change.Name, hclValueRepr(change.New)))
+	return nil
+}
+
+func (v *OutputHCL) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// scalarRawValue converts a cty.Value to the string representation used by
+// -raw and the env/dotenv formats. It returns an error describing the
+// value's type when the value isn't a plain string, number, or bool.
+func scalarRawValue(v cty.Value) (string, error) {
+	if v.IsNull() {
+		return "", fmt.Errorf("null")
+	}
+	switch {
+	case v.Type().Equals(cty.String):
+		return v.AsString(), nil
+	case v.Type().Equals(cty.Number):
+		bf := v.AsBigFloat()
+		return bf.Text('f', -1), nil
+	case v.Type().Equals(cty.Bool):
+		return strconv.FormatBool(v.True()), nil
+	default:
+		return "", fmt.Errorf("a %s, not a string, number, or bool", v.Type().FriendlyName())
+	}
+}
+
+func rawScalar(v cty.Value) string {
+	s, err := scalarRawValue(v)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// ctyValueToInterface converts a cty.Value into the plain Go value tree
+// (map[string]interface{}, []interface{}, string, float64, bool, nil) that
+// the yaml package knows how to marshal.
+func ctyValueToInterface(v cty.Value) (interface{}, error) {
+	jsonBytes, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// outputValueRepr renders an output's value as an HCL-ish expression,
+// masking sensitive values the same way regardless of which format
+// requested the rendering.
+func outputValueRepr(ov *states.OutputValue) string {
+	if ov.Sensitive {
+		return "(sensitive value)"
+	}
+	return ctyValueRepr(ov.Value)
+}
+
+// hclValueRepr is like outputValueRepr, but quotes the sensitive-value
+// placeholder so that -format=hcl output always remains valid HCL.
+func hclValueRepr(ov *states.OutputValue) string {
+	if ov.Sensitive {
+		return `"(sensitive value)"`
+	}
+	return ctyValueRepr(ov.Value)
+}
+
+// ctyValueRepr renders v as an HCL-ish expression, used both for the human
+// view and for the -format=hcl output. Strings are quoted with
+// strconv.Quote, so template sequences such as "${" and "%{" are not
+// escaped, and a string containing one does not read back as the same
+// value.
+func ctyValueRepr(v cty.Value) string {
+	if v.IsNull() {
+		return "null"
+	}
+
+	ty := v.Type()
+	switch {
+	case ty.Equals(cty.String):
+		return strconv.Quote(v.AsString())
+	case ty.Equals(cty.Number):
+		bf := v.AsBigFloat()
+		return bf.Text('f', -1)
+	case ty.Equals(cty.Bool):
+		return strconv.FormatBool(v.True())
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		var buf bytes.Buffer
+		buf.WriteString("[")
+		first := true
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			if !first {
+				buf.WriteString(", ")
+			}
+			first = false
+			buf.WriteString(ctyValueRepr(ev))
+		}
+		buf.WriteString("]")
+		return buf.String()
+	case ty.IsMapType(), ty.IsObjectType():
+		var buf bytes.Buffer
+		buf.WriteString("{\n")
+		for it := v.ElementIterator(); it.Next(); {
+			ek, ev := it.Element()
+			fmt.Fprintf(&buf, "  %s = %s\n", strconv.Quote(ek.AsString()), ctyValueRepr(ev))
+		}
+		buf.WriteString("}")
+		return buf.String()
+	default:
+		return strconv.Quote(fmt.Sprintf("%#v", v))
+	}
+}
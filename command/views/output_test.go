@@ -0,0 +1,162 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/states"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCtyValueRepr(t *testing.T) {
+	tests := map[string]struct {
+		value cty.Value
+		want  string
+	}{
+		"string": {cty.StringVal("hello"), `"hello"`},
+		"number": {cty.NumberIntVal(42), "42"},
+		"bool":   {cty.True, "true"},
+		"null":   {cty.NullVal(cty.String), "null"},
+		"list":   {cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}), `["a", "b"]`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ctyValueRepr(test.value)
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScalarRawValue(t *testing.T) {
+	tests := map[string]struct {
+		value   cty.Value
+		want    string
+		wantErr bool
+	}{
+		"string": {cty.StringVal("hello"), "hello", false},
+		"number": {cty.NumberIntVal(42), "42", false},
+		"bool":   {cty.False, "false", false},
+		"null":   {cty.NullVal(cty.String), "", true},
+		"list":   {cty.ListVal([]cty.Value{cty.StringVal("a")}), "", true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := scalarRawValue(test.value)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOutputValueRepr_sensitive(t *testing.T) {
+	ov := &states.OutputValue{Value: cty.StringVal("shh"), Sensitive: true}
+
+	if got, want := outputValueRepr(ov), "(sensitive value)"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := hclValueRepr(ov), `"(sensitive value)"`; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestBuildYAMLTarget_sensitive(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"secret": {Value: cty.StringVal("shh"), Sensitive: true},
+		"plain":  {Value: cty.StringVal("hello"), Sensitive: false},
+	}
+
+	// A single sensitive output selected by name must still be masked: this
+	// is a regression test for a bug where the single-NAME branch skipped
+	// the Sensitive check applied everywhere else.
+	target, err := buildYAMLTarget("secret", []string{"secret"}, outputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if target != "(sensitive value)" {
+		t.Errorf("sensitive output was not masked: got %#v", target)
+	}
+
+	// A single non-sensitive output is rendered normally.
+	target, err = buildYAMLTarget("plain", []string{"plain"}, outputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if target != "hello" {
+		t.Errorf("wrong result: got %#v, want %q", target, "hello")
+	}
+
+	// The bulk (no name) path masks sensitive outputs alongside plain ones.
+	bulk, err := buildYAMLTarget("", []string{"secret", "plain"}, outputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	bulkMap, ok := bulk.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a map, got %#v", bulk)
+	}
+	if bulkMap["secret"] != "(sensitive value)" {
+		t.Errorf("sensitive output was not masked in bulk output: got %#v", bulkMap["secret"])
+	}
+	if bulkMap["plain"] != "hello" {
+		t.Errorf("wrong result for plain output: got %#v", bulkMap["plain"])
+	}
+}
+
+func TestRawOutputValue(t *testing.T) {
+	if _, diags := rawOutputValue("x", cty.StringVal("hello")); diags.HasErrors() {
+		t.Errorf("unexpected error for a string: %s", diags.Err())
+	}
+
+	t.Run("null", func(t *testing.T) {
+		_, diags := rawOutputValue("x", cty.NullVal(cty.String))
+		if !diags.HasErrors() {
+			t.Fatalf("expected an error for a null value")
+		}
+	})
+
+	t.Run("non-scalar", func(t *testing.T) {
+		_, diags := rawOutputValue("x", cty.ListVal([]cty.Value{cty.StringVal("a")}))
+		if !diags.HasErrors() {
+			t.Fatalf("expected an error for a non-scalar value")
+		}
+	})
+}
+
+func TestOutputRaw_OutputChange_rejectsUnsupportedValues(t *testing.T) {
+	// A regression test for -raw -watch silently rendering non-scalar or
+	// null values as a blank line forever instead of erroring: OutputChange
+	// must apply the same validation as Output.
+	v := &OutputRaw{}
+
+	tests := map[string]cty.Value{
+		"null": cty.NullVal(cty.String),
+		"list": cty.ListVal([]cty.Value{cty.StringVal("a")}),
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			change := OutputChange{
+				Name: "mylist",
+				New:  &states.OutputValue{Value: value},
+			}
+			diags := v.OutputChange(time.Time{}, change)
+			if !diags.HasErrors() {
+				t.Fatalf("expected an error for an unsupported -raw value, got none")
+			}
+		})
+	}
+}